@@ -0,0 +1,50 @@
+package tracerr
+
+// Cause returns the wrapped error, giving *Error the same Cause() error
+// method that pkg/errors and friendsofgo/errors use, so tracerr can be
+// dropped into codebases migrating from either.
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+// unwrapOne advances err by one level of its chain, preferring the standard
+// Unwrap() error convention and falling back to the legacy Cause() error
+// convention used by pkg/errors and friendsofgo/errors. It returns nil when
+// err is the end of the chain.
+func unwrapOne(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	default:
+		return nil
+	}
+}
+
+// Cause walks err's chain to the end, following both Unwrap()-style and
+// legacy Cause()-style links, and returns the original error it was wrapped
+// around.
+func Cause(err error) error {
+	for {
+		next := unwrapOne(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// RootFrames walks err's chain to the end, following both Unwrap()-style and
+// legacy Cause()-style links, and returns the stack trace of the deepest
+// *Error found. It returns nil if no *Error appears in the chain.
+func RootFrames(err error) []Frame {
+	var frames []Frame
+	for err != nil {
+		if tracerrErr, ok := err.(*Error); ok {
+			frames = tracerrErr.Stacktrace()
+		}
+		err = unwrapOne(err)
+	}
+	return frames
+}
@@ -0,0 +1,85 @@
+package tracerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// legacyCauser mimics pkg/errors and friendsofgo/errors: a Cause() error
+// method instead of the standard Unwrap() error convention.
+type legacyCauser struct {
+	msg   string
+	cause error
+}
+
+func (e *legacyCauser) Error() string { return e.msg }
+func (e *legacyCauser) Cause() error  { return e.cause }
+
+func TestCause_UnwrapChain(t *testing.T) {
+	root := New("root")
+	wrapped := Wrap(root, "wrapped")
+	stdlib := fmt.Errorf("stdlib: %w", wrapped)
+
+	if got := Cause(stdlib); got != root {
+		t.Fatalf("Cause(stdlib) = %v, want root (%v)", got, root)
+	}
+}
+
+func TestCause_LegacyCauseChain(t *testing.T) {
+	root := errors.New("root")
+	legacy := &legacyCauser{msg: "legacy", cause: root}
+
+	if got := Cause(legacy); got != root {
+		t.Fatalf("Cause(legacy) = %v, want root (%v)", got, root)
+	}
+}
+
+func TestCause_MixedChain(t *testing.T) {
+	root := New("root")
+	legacy := &legacyCauser{msg: "legacy", cause: root}
+	stdlib := fmt.Errorf("stdlib: %w", legacy)
+
+	if got := Cause(stdlib); got != root {
+		t.Fatalf("Cause(stdlib) = %v, want root (%v)", got, root)
+	}
+}
+
+func TestCause_NoChain(t *testing.T) {
+	err := errors.New("standalone")
+	if got := Cause(err); got != err {
+		t.Fatalf("Cause(err) = %v, want err itself", got)
+	}
+}
+
+func TestError_Cause(t *testing.T) {
+	root := New("root")
+	wrapped := Wrap(root, "wrapped")
+	if got := wrapped.Cause(); got != root {
+		t.Fatalf("wrapped.Cause() = %v, want root (%v)", got, root)
+	}
+	if got := root.Cause(); got != nil {
+		t.Fatalf("root.Cause() = %v, want nil", got)
+	}
+}
+
+func TestRootFrames_MixedChain(t *testing.T) {
+	root := New("root")
+	legacy := &legacyCauser{msg: "legacy", cause: root}
+	stdlib := fmt.Errorf("stdlib: %w", legacy)
+
+	frames := RootFrames(stdlib)
+	if len(frames) == 0 {
+		t.Fatal("RootFrames(stdlib) returned no frames")
+	}
+	if got, want := frames, root.Stacktrace(); len(got) != len(want) {
+		t.Fatalf("RootFrames(stdlib) = %d frames, want %d (root's own)", len(got), len(want))
+	}
+}
+
+func TestRootFrames_NoTracerrError(t *testing.T) {
+	err := errors.New("plain")
+	if frames := RootFrames(err); frames != nil {
+		t.Fatalf("RootFrames(plain) = %v, want nil", frames)
+	}
+}
@@ -0,0 +1,64 @@
+package tracerr
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Config holds tunables for how tracerr reads and highlights source context
+// when rendering frames. FprintWith and the built-in Formatters consult
+// DefaultConfig; there is currently no per-call override.
+type Config struct {
+	// ContextLines is how many lines of source on either side of a frame's
+	// line to read and display.
+	ContextLines int
+	// ChromaStyle is the chroma style name (e.g. "monokai", "github") used
+	// to highlight source context.
+	ChromaStyle string
+	// ChromaFormatter is the chroma formatter name ANSIFormatter passes to
+	// quick.Highlight, e.g. "terminal256" or "terminal16m". HTMLFormatter
+	// always uses "html" regardless of this setting.
+	ChromaFormatter string
+	// LexerFor returns the chroma lexer name for a frame's file path. The
+	// default recognizes .s, .tmpl, and .c in addition to Go source.
+	LexerFor func(path string) string
+	// NoColor disables ANSI color output in ANSIFormatter, rendering the
+	// same plain text PlainFormatter would.
+	NoColor bool
+}
+
+// DefaultConfig is the Config consulted by DefaultFormatter and FprintWith.
+// Changing its fields takes effect on the next Fprint/FprintWith call.
+var DefaultConfig = NewConfig()
+
+// NewConfig returns a Config with tracerr's defaults: 1 line of context, the
+// monokai chroma style and terminal256 formatter, and extension-based lexer
+// detection via defaultLexerFor. NoColor defaults to true when NO_COLOR is set to a non-empty
+// value or TERM is "dumb", per https://no-color.org and terminfo convention.
+func NewConfig() *Config {
+	return &Config{
+		ContextLines:    1,
+		ChromaStyle:     "monokai",
+		ChromaFormatter: "terminal256",
+		LexerFor:        defaultLexerFor,
+		NoColor:         os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb",
+	}
+}
+
+// defaultLexerFor guesses a chroma lexer name from path's extension, falling
+// back to "go" for anything unrecognized, including extensionless paths.
+func defaultLexerFor(path string) string {
+	switch filepath.Ext(path) {
+	case ".s":
+		// Go's .s files are Plan9-style assembly, which chroma has no lexer
+		// for. "gas" (AT&T syntax) at least tokenizes registers, directives,
+		// and comments closer to Plan9 asm than "nasm" (Intel syntax) would.
+		return "gas"
+	case ".tmpl":
+		return "go-html-template"
+	case ".c":
+		return "c"
+	default:
+		return "go"
+	}
+}
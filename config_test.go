@@ -0,0 +1,74 @@
+package tracerr
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDefaultLexerFor(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "go"},
+		{"runtime.s", "gas"},
+		{"page.tmpl", "go-html-template"},
+		{"cgo.c", "c"},
+		{"noext", "go"},
+	}
+	for _, tt := range tests {
+		if got := defaultLexerFor(tt.path); got != tt.want {
+			t.Errorf("defaultLexerFor(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNewConfig_Defaults(t *testing.T) {
+	c := NewConfig()
+	if c.ContextLines != 1 {
+		t.Errorf("ContextLines = %d, want 1", c.ContextLines)
+	}
+	if c.ChromaStyle != "monokai" {
+		t.Errorf("ChromaStyle = %q, want %q", c.ChromaStyle, "monokai")
+	}
+	if c.ChromaFormatter != "terminal256" {
+		t.Errorf("ChromaFormatter = %q, want %q", c.ChromaFormatter, "terminal256")
+	}
+	if c.LexerFor == nil {
+		t.Error("LexerFor is nil")
+	}
+}
+
+func TestNewConfig_NoColorFromEnv(t *testing.T) {
+	origNoColor, hadNoColor := os.LookupEnv("NO_COLOR")
+	origTerm, hadTerm := os.LookupEnv("TERM")
+	t.Cleanup(func() {
+		if hadNoColor {
+			os.Setenv("NO_COLOR", origNoColor)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+		if hadTerm {
+			os.Setenv("TERM", origTerm)
+		} else {
+			os.Unsetenv("TERM")
+		}
+	})
+
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "xterm-256color")
+	if got := NewConfig().NoColor; got {
+		t.Error("NoColor = true, want false with no NO_COLOR and a non-dumb TERM")
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	if got := NewConfig().NoColor; !got {
+		t.Error("NoColor = false, want true when NO_COLOR is set")
+	}
+
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "dumb")
+	if got := NewConfig().NoColor; !got {
+		t.Error("NoColor = false, want true when TERM=dumb")
+	}
+}
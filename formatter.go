@@ -0,0 +1,306 @@
+package tracerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Formatter renders an error chain. FormatError writes a single error's
+// message line; FormatFrame writes one stack frame together with the source
+// context lines src (src[errLine] is the line the frame points at, or
+// errLine is -1 if src is empty because the source file couldn't be read).
+type Formatter interface {
+	FormatError(w io.Writer, e *Error)
+	FormatFrame(w io.Writer, f Frame, src []string, errLine int)
+}
+
+// DefaultFormatter is the Formatter used by Fprint and Print. It defaults to
+// ANSIFormatter, preserving tracerr's original colored terminal output.
+var DefaultFormatter Formatter = ANSIFormatter{}
+
+// SetDefaultFormatter changes the Formatter used by Fprint and Print, e.g. to
+// switch to PlainFormatter when stderr isn't a terminal.
+func SetDefaultFormatter(f Formatter) {
+	DefaultFormatter = f
+}
+
+// ChainFormatter is an optional Formatter extension for formats that nest
+// the whole cause chain into a single value (e.g. JSON) instead of
+// rendering one error at a time. FprintWith calls FormatChain in place of
+// its usual per-link loop when formatter implements it.
+type ChainFormatter interface {
+	FormatChain(w io.Writer, err error)
+}
+
+// causedBySeparator is an optional Formatter extension for formats that want
+// their own "Caused by" separator between chain links, e.g. ANSIFormatter's
+// italicized styling. FprintWith falls back to a plain "\nCaused by: " when
+// formatter doesn't implement it.
+type causedBySeparator interface {
+	causedBySeparator() string
+}
+
+// FprintWith formats and writes the full error chain and stack traces to w
+// using formatter, folding the duplicated tail of frames an inner error
+// shares with the error that wraps it.
+func FprintWith(w io.Writer, err error, formatter Formatter) {
+	if cf, ok := formatter.(ChainFormatter); ok {
+		cf.FormatChain(w, err)
+		return
+	}
+
+	sep := "\nCaused by: "
+	if cs, ok := formatter.(causedBySeparator); ok {
+		sep = cs.causedBySeparator()
+	}
+
+	var currentErr error = err
+	isFirst := true
+	var prevFrames []Frame
+
+	for currentErr != nil {
+		tracerrErr, ok := currentErr.(*Error)
+
+		if !isFirst {
+			fmt.Fprint(w, sep)
+		}
+
+		if ok {
+			formatter.FormatError(w, tracerrErr)
+			frames := tracerrErr.Stacktrace()
+			shared := 0
+			if prevFrames != nil {
+				shared = sharedSuffixLen(frames, prevFrames)
+			}
+			for _, frame := range frames[:len(frames)-shared] {
+				src, errLine := frameSource(frame)
+				formatter.FormatFrame(w, frame, src, errLine)
+			}
+			if shared > 0 {
+				fmt.Fprintf(w, "  ... %d frames in common with above ...\n", shared)
+			}
+			prevFrames = frames
+		} else {
+			formatter.FormatError(w, &Error{Msg: currentErr.Error()})
+			prevFrames = nil
+		}
+
+		currentErr = errors.Unwrap(currentErr)
+		isFirst = false
+	}
+}
+
+// ownMessage returns err's message with its cause's message (as produced by
+// the common "...: %w" / "Msg: cause" wrapping convention) stripped off the
+// end, so wrapping a non-*Error around an already-wrapped error doesn't
+// duplicate the cause's text when each link is serialized separately (e.g.
+// by JSONFormatter.FormatChain, which nests the cause under its own field).
+func ownMessage(err error) string {
+	msg := err.Error()
+	if cause := errors.Unwrap(err); cause != nil {
+		if suffix := ": " + cause.Error(); strings.HasSuffix(msg, suffix) {
+			return strings.TrimSuffix(msg, suffix)
+		}
+	}
+	return msg
+}
+
+// frameSource reads DefaultConfig.ContextLines of source around frame.Line,
+// returning the lines and the index within them that frame.Line corresponds
+// to. It returns (nil, -1) if the source file can't be read.
+func frameSource(frame Frame) ([]string, int) {
+	lines, startLine, err := readSourceContextLines(frame.File, frame.Line, DefaultConfig.ContextLines)
+	if err != nil {
+		return nil, -1
+	}
+	return lines, frame.Line - startLine
+}
+
+// ANSIFormatter renders errors and frames with ANSI colors and
+// terminal256-highlighted source, tracerr's original look.
+type ANSIFormatter struct{}
+
+// FormatError writes e's message in red, or plain if DefaultConfig.NoColor.
+func (ANSIFormatter) FormatError(w io.Writer, e *Error) {
+	if DefaultConfig.NoColor {
+		fmt.Fprintf(w, "%s\n", e.Msg)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", red(e.Msg))
+}
+
+// causedBySeparator italicizes "Caused by: ", tracerr's original separator
+// styling, or falls back to plain text if DefaultConfig.NoColor.
+func (ANSIFormatter) causedBySeparator() string {
+	if DefaultConfig.NoColor {
+		return "\nCaused by: "
+	}
+	return "\n" + italic("Caused by: ")
+}
+
+// FormatFrame writes f with a gray location, yellow function name, and the
+// source context syntax-highlighted per DefaultConfig. If DefaultConfig.NoColor
+// is set, it renders the same plain text PlainFormatter would.
+func (ANSIFormatter) FormatFrame(w io.Writer, f Frame, src []string, errLine int) {
+	if DefaultConfig.NoColor {
+		PlainFormatter{}.FormatFrame(w, f, src, errLine)
+		return
+	}
+
+	location := gray(fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line))
+	function := yellow(f.Function)
+	fmt.Fprintf(w, "  at %s (%s)\n", function, location)
+
+	if src == nil {
+		fmt.Fprintf(w, "    %s\n", gray("Could not read source file"))
+		return
+	}
+
+	lexer := DefaultConfig.LexerFor(f.File)
+	var highlightedBuf bytes.Buffer
+	if err := quick.Highlight(&highlightedBuf, strings.Join(src, "\n"), lexer, DefaultConfig.ChromaFormatter, DefaultConfig.ChromaStyle); err != nil {
+		highlightedBuf.WriteString(strings.Join(src, "\n"))
+	}
+	highlightedLines := strings.Split(highlightedBuf.String(), "\n")
+	lineNumWidth := len(fmt.Sprintf("%d", f.Line+len(src)-errLine-1))
+
+	for i, hLine := range highlightedLines {
+		if i >= len(src) {
+			continue
+		}
+		lineNum := f.Line - errLine + i
+		gutter := gray(fmt.Sprintf("  %*d | ", lineNumWidth, lineNum))
+		if i == errLine {
+			gutter = boldGray(fmt.Sprintf("  %*d | ", lineNumWidth, lineNum))
+		}
+		fmt.Fprintf(w, "%s%s\n", gutter, hLine)
+	}
+}
+
+// PlainFormatter renders errors and frames as uncolored text, suitable for
+// CI logs and files where ANSI escapes would just be noise.
+type PlainFormatter struct{}
+
+// FormatError writes e's message with no styling.
+func (PlainFormatter) FormatError(w io.Writer, e *Error) {
+	fmt.Fprintf(w, "%s\n", e.Msg)
+}
+
+// FormatFrame writes f's location, function name, and plain source context.
+func (PlainFormatter) FormatFrame(w io.Writer, f Frame, src []string, errLine int) {
+	fmt.Fprintf(w, "  at %s (%s:%d)\n", f.Function, filepath.Base(f.File), f.Line)
+	if src == nil {
+		fmt.Fprintf(w, "    Could not read source file\n")
+		return
+	}
+	lineNumWidth := len(fmt.Sprintf("%d", f.Line+len(src)-errLine-1))
+	for i, line := range src {
+		lineNum := f.Line - errLine + i
+		marker := " "
+		if i == errLine {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "  %s%*d | %s\n", marker, lineNumWidth, lineNum, line)
+	}
+}
+
+// HTMLFormatter renders errors and frames as HTML fragments, with source
+// context highlighted by chroma's html formatter, suitable for embedding in
+// an error page.
+type HTMLFormatter struct{}
+
+// FormatError writes e's message inside a <p class="tracerr-msg"> element.
+func (HTMLFormatter) FormatError(w io.Writer, e *Error) {
+	fmt.Fprintf(w, "<p class=\"tracerr-msg\">%s</p>\n", html.EscapeString(e.Msg))
+}
+
+// FormatFrame writes f's location and function name, followed by a <pre>
+// block of syntax-highlighted source context with the failing line's
+// background highlighted (chroma's HighlightLines), so the one thing that
+// matters most on an error page — which line broke — is still visible.
+func (HTMLFormatter) FormatFrame(w io.Writer, f Frame, src []string, errLine int) {
+	fmt.Fprintf(w, "<div class=\"tracerr-frame\"><span class=\"tracerr-func\">%s</span> "+
+		"<span class=\"tracerr-loc\">%s:%d</span></div>\n",
+		html.EscapeString(f.Function), html.EscapeString(filepath.Base(f.File)), f.Line)
+	if src == nil {
+		fmt.Fprintf(w, "<pre class=\"tracerr-nosource\">Could not read source file</pre>\n")
+		return
+	}
+	lexer := DefaultConfig.LexerFor(f.File)
+	if err := highlightHTMLFragment(w, strings.Join(src, "\n"), lexer, DefaultConfig.ChromaStyle, errLine); err != nil {
+		fmt.Fprintf(w, "<pre>%s</pre>\n", html.EscapeString(strings.Join(src, "\n")))
+	}
+}
+
+// highlightHTMLFragment renders source as a self-contained <pre> fragment
+// with inline styles, highlighting the 0-based line errLine. It bypasses
+// quick.Highlight because quick's registered "html" formatter is configured
+// with Standalone(true), which wraps every call in a full <html>/<style>
+// document — unusable for embedding one fragment per frame in a page.
+func highlightHTMLFragment(w io.Writer, source, lexerName, styleName string, errLine int) error {
+	l := lexers.Get(lexerName)
+	if l == nil {
+		l = lexers.Analyse(source)
+	}
+	if l == nil {
+		l = lexers.Fallback
+	}
+	l = chroma.Coalesce(l)
+
+	s := styles.Get(styleName)
+	if s == nil {
+		s = styles.Fallback
+	}
+
+	var opts []chromahtml.Option
+	if errLine >= 0 {
+		opts = append(opts, chromahtml.HighlightLines([][2]int{{errLine + 1, errLine + 1}}))
+	}
+	formatter := chromahtml.New(opts...)
+
+	it, err := l.Tokenise(nil, source)
+	if err != nil {
+		return err
+	}
+	return formatter.Format(w, s, it)
+}
+
+// JSONFormatter renders the whole error chain as a single JSON object via
+// Error.MarshalJSON, which already nests the cause chain and every frame.
+type JSONFormatter struct{}
+
+// FormatChain writes err as one JSON object followed by a newline. If err
+// isn't a *Error, it's wrapped so the chain is still walked via Unwrap.
+func (JSONFormatter) FormatChain(w io.Writer, err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Msg: ownMessage(err), cause: errors.Unwrap(err)}
+	}
+	b, marshalErr := json.Marshal(e)
+	if marshalErr != nil {
+		fmt.Fprintf(w, `{"msg":%q}`+"\n", e.Msg)
+		return
+	}
+	w.Write(b)
+	fmt.Fprint(w, "\n")
+}
+
+// FormatError is unused: FprintWith dispatches JSONFormatter through
+// FormatChain instead, since JSON nests the whole chain into one object.
+func (JSONFormatter) FormatError(w io.Writer, e *Error) {}
+
+// FormatFrame is unused: JSONFormatter.FormatChain already serializes every
+// frame as part of the error object.
+func (JSONFormatter) FormatFrame(w io.Writer, f Frame, src []string, errLine int) {}
@@ -0,0 +1,69 @@
+package tracerr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSharedSuffixLen(t *testing.T) {
+	a := []Frame{
+		{File: "a.go", Line: 1, Function: "A"},
+		{File: "b.go", Line: 2, Function: "B"},
+		{File: "c.go", Line: 3, Function: "C"},
+	}
+	b := []Frame{
+		{File: "x.go", Line: 9, Function: "X"},
+		{File: "b.go", Line: 2, Function: "B"},
+		{File: "c.go", Line: 3, Function: "C"},
+	}
+
+	if n := sharedSuffixLen(a, b); n != 2 {
+		t.Fatalf("sharedSuffixLen = %d, want 2", n)
+	}
+	if n := sharedSuffixLen(a, a); n != len(a) {
+		t.Fatalf("sharedSuffixLen(a, a) = %d, want %d", n, len(a))
+	}
+	if n := sharedSuffixLen(a, nil); n != 0 {
+		t.Fatalf("sharedSuffixLen(a, nil) = %d, want 0", n)
+	}
+}
+
+func wrappedAt(root *Error) *Error {
+	return Wrap(root, "wrapped")
+}
+
+func TestFprintWith_FoldsSharedSuffix(t *testing.T) {
+	root := New("root cause")
+	wrapped := wrappedAt(root)
+
+	var buf bytes.Buffer
+	FprintWith(&buf, wrapped, PlainFormatter{})
+	out := buf.String()
+
+	if !strings.Contains(out, "frames in common with above") {
+		t.Fatalf("expected folded-suffix message, got:\n%s", out)
+	}
+	// The folded message must report fewer frames than root's own
+	// stacktrace, since both share the test runner frames below this test,
+	// but root's frame here (the New call) is on a different line than
+	// wrapped's (the wrappedAt call), and wrappedAt's frame is wrapped's alone.
+	shared := sharedSuffixLen(root.Stacktrace(), wrapped.Stacktrace())
+	if shared == 0 {
+		t.Fatal("expected root and wrapped to share at least one frame")
+	}
+	if shared >= len(root.Stacktrace()) {
+		t.Fatalf("expected root to also have frames wrapped doesn't share, shared=%d total=%d", shared, len(root.Stacktrace()))
+	}
+}
+
+func TestFprintWith_NoCauseSectionForSingleError(t *testing.T) {
+	err := New("standalone error")
+
+	var buf bytes.Buffer
+	FprintWith(&buf, err, PlainFormatter{})
+
+	if strings.Contains(buf.String(), "Caused by") {
+		t.Fatalf("single error shouldn't print a Caused by section:\n%s", buf.String())
+	}
+}
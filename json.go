@@ -0,0 +1,108 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// JSONContextLines controls how many lines of source context on either side
+// of the frame's line MarshalJSON embeds per frame. The default, 0, omits the
+// "source" field entirely so JSON output stays compact for log pipelines.
+var JSONContextLines = 0
+
+// frameJSON is the wire representation produced by Frame.MarshalJSON.
+type frameJSON struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Function string   `json:"function"`
+	Source   []string `json:"source,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. It includes a "source" snippet of
+// up to JSONContextLines lines on either side of the frame's line (plus that
+// line itself) when JSONContextLines > 0.
+func (f Frame) MarshalJSON() ([]byte, error) {
+	fj := frameJSON{File: f.File, Line: f.Line, Function: f.Function}
+	if JSONContextLines > 0 {
+		if lines, _, err := readSourceContextLines(f.File, f.Line, JSONContextLines); err == nil {
+			fj.Source = lines
+		}
+	}
+	return json.Marshal(fj)
+}
+
+// errorJSON is the wire representation produced by Error.MarshalJSON.
+type errorJSON struct {
+	Msg    string          `json:"msg"`
+	Frames []Frame         `json:"frames"`
+	Cause  json.RawMessage `json:"cause,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The cause chain is serialized
+// recursively via errors.Unwrap: a wrapped *Error marshals through its own
+// MarshalJSON (so nested frames survive), while a plain error is reduced to
+// its message so the output never depends on an unknown concrete type.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	ej := errorJSON{Msg: e.Msg, Frames: e.Stacktrace()}
+	if e.cause != nil {
+		causeJSON, err := marshalCause(e.cause)
+		if err != nil {
+			return nil, err
+		}
+		ej.Cause = causeJSON
+	}
+	return json.Marshal(ej)
+}
+
+// marshalCause serializes a cause error, recursing through tracerr.Errors so
+// the full chain ends up nested under successive "cause" fields.
+func marshalCause(err error) (json.RawMessage, error) {
+	if tracerrErr, ok := err.(*Error); ok {
+		return json.Marshal(tracerrErr)
+	}
+	b, marshalErr := json.Marshal(struct {
+		Msg string `json:"msg"`
+	}{Msg: err.Error()})
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	return b, nil
+}
+
+// LogObjectEncoder is tracerr's own minimal field-sink interface. Go
+// requires a method's parameter type to match exactly for interface
+// satisfaction, so *Error does not (and cannot, without importing them)
+// implement zap's zapcore.ObjectMarshaler or any other logger's native
+// marshaler interface just by having a same-shaped method. To feed a real
+// structured logger, write a small adapter type around its encoder (e.g. a
+// one-line wrapper whose AddString/AddInt call the logger's own Str/Int or
+// AddString methods) and pass that adapter to MarshalLogObject.
+type LogObjectEncoder interface {
+	AddString(key, value string)
+	AddInt(key string, value int)
+}
+
+// MarshalLogObject writes the error's message, frames (as a JSON array
+// string), and cause (via errors.Unwrap) into enc. enc is typically a small
+// adapter around a structured logger's own encoder/event type; see
+// LogObjectEncoder's doc comment.
+func (e *Error) MarshalLogObject(enc LogObjectEncoder) error {
+	enc.AddString("msg", e.Msg)
+	framesJSON, err := json.Marshal(e.Stacktrace())
+	if err != nil {
+		return err
+	}
+	enc.AddString("frames", string(framesJSON))
+	if cause := errors.Unwrap(e); cause != nil {
+		enc.AddString("cause", cause.Error())
+	}
+	return nil
+}
+
+// MarshalLogObject writes the frame's file, line, and function into enc.
+func (f Frame) MarshalLogObject(enc LogObjectEncoder) error {
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+	enc.AddString("function", f.Function)
+	return nil
+}
@@ -0,0 +1,83 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFrameMarshalJSON(t *testing.T) {
+	f := Frame{File: "/src/foo.go", Line: 42, Function: "pkg.Foo"}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got frameJSON
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.File != f.File || got.Line != f.Line || got.Function != f.Function {
+		t.Fatalf("got %+v, want file=%s line=%d function=%s", got, f.File, f.Line, f.Function)
+	}
+	if got.Source != nil {
+		t.Fatalf("Source = %v, want nil when JSONContextLines is 0", got.Source)
+	}
+}
+
+func TestErrorMarshalJSON_NestedCause(t *testing.T) {
+	root := New("root cause")
+	wrapped := Wrap(root, "wrapped")
+
+	b, err := json.Marshal(wrapped)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got errorJSON
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Msg != "wrapped" {
+		t.Fatalf("Msg = %q, want %q", got.Msg, "wrapped")
+	}
+	if got.Cause == nil {
+		t.Fatal("Cause is nil, want the nested root error")
+	}
+
+	var cause errorJSON
+	if err := json.Unmarshal(got.Cause, &cause); err != nil {
+		t.Fatalf("Unmarshal cause: %v", err)
+	}
+	if cause.Msg != "root cause" {
+		t.Fatalf("cause.Msg = %q, want %q", cause.Msg, "root cause")
+	}
+	if cause.Cause != nil {
+		t.Fatalf("cause.Cause = %s, want nil at the root", cause.Cause)
+	}
+}
+
+func TestErrorMarshalJSON_NonTracerrCause(t *testing.T) {
+	wrapped := Wrap(errors.New("plain cause"), "wrapped")
+
+	b, err := json.Marshal(wrapped)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got errorJSON
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	var cause struct {
+		Msg string `json:"msg"`
+	}
+	if err := json.Unmarshal(got.Cause, &cause); err != nil {
+		t.Fatalf("Unmarshal cause: %v", err)
+	}
+	if cause.Msg != "plain cause" {
+		t.Fatalf("cause.Msg = %q, want %q", cause.Msg, "plain cause")
+	}
+}
@@ -0,0 +1,124 @@
+package tracerr
+
+import (
+	"bufio"
+	"container/list"
+	"os"
+	"sync"
+	"time"
+)
+
+// sourceCacheEntry is the value stored per path in sourceCache's LRU list.
+type sourceCacheEntry struct {
+	path    string
+	lines   []string
+	modTime time.Time
+}
+
+// sourceCache is an LRU cache of whole-file line slices, keyed by path, used
+// to avoid re-reading and re-scanning the same source file on every
+// print of a frame (via readSourceContextLines). Entries are invalidated by mtime rather than
+// evicted on write, since source files tracerr reads are rarely touched by a
+// running process that's already failed and is printing the error.
+type sourceCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newSourceCache(capacity int) *sourceCache {
+	return &sourceCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// lines returns path's full contents split into lines, using the cached
+// copy if path's mtime hasn't changed since it was cached.
+func (c *sourceCache) lines(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if el, ok := c.items[path]; ok {
+		entry := el.Value.(*sourceCacheEntry)
+		if entry.modTime.Equal(fi.ModTime()) {
+			c.ll.MoveToFront(el)
+			lines := entry.lines
+			c.mu.Unlock()
+			return lines, nil
+		}
+		c.ll.Remove(el)
+		delete(c.items, path)
+	}
+	c.mu.Unlock()
+
+	lines, err := readAllLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Another goroutine may have raced us to read and insert the same path
+	// while we were reading it ourselves; reuse its entry instead of
+	// inserting a second one that would orphan one of the two list nodes.
+	if el, ok := c.items[path]; ok {
+		entry := el.Value.(*sourceCacheEntry)
+		if entry.modTime.Equal(fi.ModTime()) {
+			c.ll.MoveToFront(el)
+			return entry.lines, nil
+		}
+		c.ll.Remove(el)
+		delete(c.items, path)
+	}
+	el := c.ll.PushFront(&sourceCacheEntry{path: path, lines: lines, modTime: fi.ModTime()})
+	c.items[path] = el
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*sourceCacheEntry).path)
+	}
+	return lines, nil
+}
+
+// readAllLines reads path's entire contents into a slice of lines.
+func readAllLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// DefaultSourceCacheSize is the number of source files the source-file cache
+// holds until SetSourceCacheSize is called.
+const DefaultSourceCacheSize = 128
+
+// defaultSourceCache backs readSourceContextLines.
+var defaultSourceCache = newSourceCache(DefaultSourceCacheSize)
+
+// SetSourceCacheSize resizes the source-file cache readSourceContextLines
+// uses, discarding whatever is currently cached. Call it once at startup if
+// DefaultSourceCacheSize doesn't fit your process's working set (e.g. a
+// large monorepo with many distinct frames, or a memory-constrained one).
+func SetSourceCacheSize(n int) {
+	defaultSourceCache = newSourceCache(n)
+}
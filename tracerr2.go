@@ -3,17 +3,14 @@
 package tracerr
 
 import (
-	"bufio"
-	"bytes"
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
-
-	"github.com/alecthomas/chroma/v2/quick"
+	"sync"
 )
 
 // ANSI color and formatting constants for terminal output.
@@ -33,11 +30,72 @@ type Frame struct {
 	Function string // The name of the function.
 }
 
+// Format implements fmt.Formatter. It supports the following verbs:
+//
+//	%s    function name
+//	%+s   function name and full file path, on two lines
+//	%d    line number
+//	%v    equivalent to %s:%d
+//	%+v   function, file:line, and the source line itself
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			io.WriteString(s, f.Function)
+			io.WriteString(s, "\n\t")
+			io.WriteString(s, f.File)
+			return
+		}
+		io.WriteString(s, f.Function)
+	case 'd':
+		io.WriteString(s, strconv.Itoa(f.Line))
+	case 'v':
+		if s.Flag('+') {
+			f.Format(s, 's')
+			io.WriteString(s, ":")
+			f.Format(s, 'd')
+			if lines, _, err := readSourceContextLines(f.File, f.Line, 0); err == nil && len(lines) > 0 {
+				io.WriteString(s, "\n\t")
+				io.WriteString(s, strings.TrimSpace(lines[0]))
+			}
+			return
+		}
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// StackTrace is a slice of Frame and implements fmt.Formatter so an entire
+// trace can be rendered with a single verb, mirroring Frame's verbs.
+type StackTrace []Frame
+
+// Format implements fmt.Formatter, printing one frame per line for %v and %+v.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		for _, f := range st {
+			io.WriteString(s, "\n")
+			f.Format(s, verb)
+		}
+	}
+}
+
 // Error represents an error with an associated stack trace and a potential cause.
 type Error struct {
-	Msg    string  // The error message.
-	Frames []Frame // The stack trace frames.
-	cause  error   // The wrapped error.
+	Msg string // The error message.
+
+	// Frames holds the stack trace. It starts nil and is filled in by the
+	// first Stacktrace() call rather than at creation, since symbolizing
+	// pcs is deferred per newError's doc comment; Fprint, Print, and the
+	// JSON/log marshalers all call Stacktrace() internally, so Frames is
+	// already populated by the time any of them return. Call Stacktrace()
+	// yourself before reading Frames directly.
+	Frames []Frame
+
+	cause error // The wrapped error.
+	pcs   []uintptr
+	once  sync.Once // Guards Frames against concurrent Stacktrace() calls.
 }
 
 // New creates a new Tracerr error with a message and a stack trace.
@@ -74,36 +132,50 @@ func Wrapf(err error, format string, args ...interface{}) *Error {
 	return e
 }
 
+// maxStackDepth bounds how many program counters newError captures per
+// error, mirroring pkg/errors' stack depth.
+const maxStackDepth = 32
+
 // newError is the internal helper to create an error and capture the stack.
-// The 'skip' parameter indicates how many stack frames to ascend.
+// The 'skip' parameter indicates how many stack frames to ascend. Capture
+// only records the raw program counters; resolving them into Frames (file,
+// line, function name) is deferred to the first Stacktrace() call, since
+// symbolizing program counters via runtime.CallersFrames is expensive and
+// often never needed (e.g. an error that's only ever compared with Is/As).
 func newError(msg string, skip int) *Error {
-	frames := make([]Frame, 0, 10)
-	for i := skip; ; i++ {
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
-		}
-		fn := runtime.FuncForPC(pc)
-		var funcName string
-		if fn != nil {
-			funcName = filepath.Base(fn.Name())
-		} else {
-			funcName = "<unknown>"
-		}
-		// Stop capturing frames when we reach the Go runtime entry points.
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+	return &Error{
+		Msg: msg,
+		pcs: pcs[:n],
+	}
+}
+
+// resolveFrames symbolizes pcs into Frames via runtime.CallersFrames,
+// stopping at the Go runtime entry points the way the original eager
+// implementation did.
+func resolveFrames(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(pcs))
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		rf, more := callersFrames.Next()
+		funcName := filepath.Base(rf.Function)
 		if strings.HasPrefix(funcName, "runtime.") {
 			break
 		}
 		frames = append(frames, Frame{
-			File:     file,
-			Line:     line,
+			File:     rf.File,
+			Line:     rf.Line,
 			Function: funcName,
 		})
+		if !more {
+			break
+		}
 	}
-	return &Error{
-		Msg:    msg,
-		Frames: frames,
-	}
+	return frames
 }
 
 // Error returns the error message, including messages from wrapped errors.
@@ -119,115 +191,67 @@ func (e *Error) Unwrap() error {
 	return e.cause
 }
 
+// Stacktrace returns the captured stack trace, giving programmatic access
+// to the frames without going through Fprint. The frames are symbolized
+// from the captured program counters on first call (safe to call
+// concurrently) and cached afterward.
+func (e *Error) Stacktrace() []Frame {
+	e.once.Do(func() {
+		e.Frames = resolveFrames(e.pcs)
+	})
+	return e.Frames
+}
+
 // Print prints the error message and stack trace to os.Stderr.
 func (e *Error) Print() {
 	e.Fprint(os.Stderr)
 }
 
-// Fprint formats and writes the full error chain and stack traces to the given writer.
-// It includes the error message, stack frames, and highlighted source code context for each error in the chain.
+// Fprint formats and writes the full error chain and stack traces to the given writer,
+// rendered with DefaultFormatter. It includes the error message, stack frames, and
+// highlighted source code context for each error in the chain.
 func (e *Error) Fprint(w io.Writer) {
-	var currentErr error = e
-	isFirst := true
-
-	for currentErr != nil {
-		// Check if the current error in the chain is a *tracerr.Error
-		tracerrErr, ok := currentErr.(*Error)
-
-		if !isFirst {
-			fmt.Fprintf(w, "\n%sCaused by: %s", formatItalic, colorReset)
-		}
-
-		if ok {
-			// It's a tracerr error, print its message and stack trace.
-			fmt.Fprintf(w, "%s\n", red(tracerrErr.Msg))
-			for _, frame := range tracerrErr.Frames {
-				printFrame(w, frame)
-			}
-		} else {
-			// It's a standard error, just print its message.
-			fmt.Fprintf(w, "%s\n", red(currentErr.Error()))
-		}
-
-		// Move to the next error in the chain.
-		currentErr = errors.Unwrap(currentErr)
-		isFirst = false
-	}
+	FprintWith(w, e, DefaultFormatter)
 }
 
-// printFrame formats and prints a single stack frame with source code context.
-func printFrame(w io.Writer, frame Frame) {
-	location := gray(fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line))
-	function := yellow(frame.Function)
-	fmt.Fprintf(w, "  at %s (%s)\n", function, location)
-
-	lines, startLine, err := readSourceContextLines(frame.File, frame.Line, 1)
-	if err != nil {
-		fmt.Fprintf(w, "    %s\n", gray("Could not read source file"))
-		return
-	}
-
-	codeBlock := strings.Join(lines, "\n")
-	var highlightedBuf bytes.Buffer
-	err = quick.Highlight(&highlightedBuf, codeBlock, "go", "terminal256", "monokai")
-	if err != nil {
-		highlightedBuf.WriteString(codeBlock)
-	}
-	highlightedLines := strings.Split(highlightedBuf.String(), "\n")
-
-	lineNumWidth := len(fmt.Sprintf("%d", startLine+len(lines)-1))
-	errorLineIndex := frame.Line - startLine
-
-	for i, hLine := range highlightedLines {
-		if i >= len(lines) {
-			continue
-		}
-		lineNum := startLine + i
-		isErrorLine := i == errorLineIndex
-
-		var gutter string
-		if isErrorLine {
-			gutter = boldGray(fmt.Sprintf("  %*d | ", lineNumWidth, lineNum))
-		} else {
-			gutter = gray(fmt.Sprintf("  %*d | ", lineNumWidth, lineNum))
-		}
-
-		fmt.Fprintf(w, "%s%s\n", gutter, hLine)
+// sharedSuffixLen returns how many trailing frames a and b have in common,
+// comparing from the bottom (oldest call) up while File, Line, and Function
+// all match. It lets FprintWith fold the duplicated tail of an inner error's
+// stack trace that an outer wrapper already printed.
+func sharedSuffixLen(a, b []Frame) int {
+	i, j := len(a)-1, len(b)-1
+	n := 0
+	for i >= 0 && j >= 0 && a[i] == b[j] {
+		n++
+		i--
+		j--
 	}
+	return n
 }
 
-// readSourceContextLines reads a specified number of lines of context from a file
-// around a central line number. It returns the lines, the starting line number, and an error.
+// readSourceContextLines reads a specified number of lines of context from a
+// file around a central line number, via defaultSourceCache so repeated
+// prints of the same frame don't re-read and re-scan the file from disk. It
+// returns the lines, the starting line number, and an error.
 func readSourceContextLines(filePath string, centerLine, context int) ([]string, int, error) {
-	file, err := os.Open(filePath)
+	allLines, err := defaultSourceCache.lines(filePath)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer file.Close()
 
 	start := centerLine - context
 	if start < 1 {
 		start = 1
 	}
 	end := centerLine + context
-
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	currentLine := 0
-	for scanner.Scan() {
-		currentLine++
-		if currentLine >= start && currentLine <= end {
-			lines = append(lines, scanner.Text())
-		}
-		if currentLine > end {
-			break
-		}
+	if end > len(allLines) {
+		end = len(allLines)
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, 0, err
+	if start > len(allLines) {
+		return nil, 0, fmt.Errorf("no lines found in range")
 	}
 
+	lines := allLines[start-1 : end]
 	if len(lines) == 0 {
 		return nil, 0, fmt.Errorf("no lines found in range")
 	}
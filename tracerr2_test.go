@@ -0,0 +1,70 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestFrameFormat(t *testing.T) {
+	f := Frame{File: "/src/pkg/foo.go", Line: 42, Function: "pkg.Foo"}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "pkg.Foo"},
+		{"%+s", "pkg.Foo\n\t/src/pkg/foo.go"},
+		{"%d", "42"},
+		{"%v", "pkg.Foo:42"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, f); got != tt.want {
+			t.Errorf("Sprintf(%q, f) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFrameFormatPlusV_NoSourceFile(t *testing.T) {
+	f := Frame{File: "/does/not/exist.go", Line: 1, Function: "pkg.Foo"}
+	// +v tries to read a source line to append; a missing file must not
+	// error or panic, just omit that line.
+	want := "pkg.Foo\n\t/does/not/exist.go:1"
+	if got := fmt.Sprintf("%+v", f); got != want {
+		t.Errorf("Sprintf(%%+v, f) = %q, want %q", got, want)
+	}
+}
+
+func TestStackTraceFormat(t *testing.T) {
+	st := StackTrace{
+		{File: "a.go", Line: 1, Function: "A"},
+		{File: "b.go", Line: 2, Function: "B"},
+	}
+	got := fmt.Sprintf("%v", st)
+	want := "\nA:1\nB:2"
+	if got != want {
+		t.Errorf("Sprintf(%%v, st) = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = New("benchmark error")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	err := New("root cause")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Wrap(err, "wrapped")
+	}
+}
+
+func BenchmarkFprint(b *testing.B) {
+	err := Wrap(New("root cause"), "wrapped")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err.Fprint(io.Discard)
+	}
+}